@@ -0,0 +1,237 @@
+package ksuidx
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+var (
+	errFormatUnknown = fmt.Errorf("unrecognized id format")
+	errFormatLength  = fmt.Errorf("invalid length for tagged id format")
+)
+
+// Format identifies the on-the-wire encoding used by an ID.
+type Format byte
+
+const (
+	// FormatV1KSUID3 is the original 3-byte ascii namespace + 20-byte KSUID
+	// layout. It has no leading tag of its own: FromBytes and Parse infer it
+	// from the overall length of the input, so ids minted before the v2
+	// formats existed keep decoding exactly as they always have.
+	FormatV1KSUID3 Format = iota
+
+	// FormatV2KSUIDN is FormatV1KSUID3 with the fixed 3-byte namespace
+	// replaced by a variable-length one (minNamespaceLengthV2-maxNamespaceLength bytes),
+	// prefixed by a format tag and a namespace-length byte.
+	FormatV2KSUIDN
+
+	// FormatV2Nano is FormatV2KSUIDN with the KSUID's second-resolution
+	// timestamp replaced by a 64-bit nanosecond timestamp plus 12 bytes of
+	// entropy.
+	FormatV2Nano
+)
+
+func (f Format) isV2() bool {
+	return f == FormatV2KSUIDN || f == FormatV2Nano
+}
+
+// nanoID is the payload used by FormatV2Nano: a nanosecond-resolution
+// timestamp plus 12 bytes of entropy, the same 20 bytes a KSUID occupies
+type nanoID struct {
+	ts      uint64
+	entropy [12]byte
+}
+
+// NewWithFormat constructs a new ID for ns using format f, panicking if
+// generation fails or ns isn't valid for f. Prefer this over New when ns is
+// longer than 3 bytes or f uses a nanosecond-resolution timestamp
+func NewWithFormat(ns Namespace, f Format) ID {
+	id, err := NewRandomWithFormat(ns, f, time.Now())
+	if err != nil {
+		panic(fmt.Sprintf("Couldn't generate id, inconceivable! error: %v", err))
+	}
+	return id
+}
+
+// NewRandomWithFormat is NewRandomWithTime with an explicit Format
+func NewRandomWithFormat(ns Namespace, f Format, t time.Time) (id ID, err error) {
+	switch f {
+	case FormatV1KSUID3:
+		if ns.length != nsLength {
+			return ID{}, errNamespaceSize
+		}
+		return NewRandomWithTime(ns, t)
+
+	case FormatV2KSUIDN:
+		if ns.length < minNamespaceLengthV2 || ns.length > maxNamespaceLength {
+			return ID{}, errNamespaceLength
+		}
+		v, err := ksuid.NewRandomWithTime(t)
+		if err != nil {
+			return ID{}, err
+		}
+		return ID{format: f, ns: ns, ksuid: v}, nil
+
+	case FormatV2Nano:
+		if ns.length < minNamespaceLengthV2 || ns.length > maxNamespaceLength {
+			return ID{}, errNamespaceLength
+		}
+		var entropy [12]byte
+		if _, err := rand.Read(entropy[:]); err != nil {
+			return ID{}, err
+		}
+		return ID{
+			format: f,
+			ns:     ns,
+			nano: nanoID{
+				ts:      uint64(t.UnixNano()),
+				entropy: entropy,
+			},
+		}, nil
+
+	default:
+		return ID{}, fmt.Errorf("%w: %v", errFormatUnknown, f)
+	}
+}
+
+// payload returns the 20 bytes following the namespace, regardless of how
+// format interprets them
+func (i ID) payload() []byte {
+	if i.format == FormatV2Nano {
+		b := make([]byte, 0, baseLength)
+		b = binary.BigEndian.AppendUint64(b, i.nano.ts)
+		return append(b, i.nano.entropy[:]...)
+	}
+	return i.ksuid[:]
+}
+
+// appendV2 appends the textual representation of a v2 id: a 1-char format
+// tag, a 2-char hex namespace length, the raw namespace bytes, then the
+// payload base62 encoded using the same alphabet as KSUID.String()
+func (i ID) appendV2(b []byte) []byte {
+	b = append(b, formatChar(i.format))
+	b = append(b, []byte(fmt.Sprintf("%02x", i.ns.length))...)
+	b = i.ns.Append(b)
+
+	payload, _ := ksuid.FromBytes(i.payload())
+	return payload.Append(b)
+}
+
+// bytesV2 returns the binary representation of a v2 id: a 1-byte format tag,
+// a 1-byte namespace length, the raw namespace bytes, then the 20-byte
+// payload
+func (i ID) bytesV2() []byte {
+	b := make([]byte, 0, 2+int(i.ns.length)+baseLength)
+	b = append(b, byte(i.format), i.ns.length)
+	b = i.ns.Append(b)
+	return append(b, i.payload()...)
+}
+
+func fromBytesTagged(b []byte) (id ID, err error) {
+	if len(b) < 2 {
+		return ID{}, errFormatLength
+	}
+
+	format := Format(b[0])
+	if !format.isV2() {
+		return ID{}, fmt.Errorf("%w: %#x", errFormatUnknown, b[0])
+	}
+
+	nsLen := int(b[1])
+	if want := 2 + nsLen + baseLength; len(b) != want {
+		return ID{}, errFormatLength
+	}
+
+	ns, err := NewNamespaceN(b[2 : 2+nsLen])
+	if err != nil {
+		return ID{}, err
+	}
+
+	id.format = format
+	id.ns = ns
+
+	payload := b[2+nsLen:]
+	if format == FormatV2Nano {
+		id.nano.ts = binary.BigEndian.Uint64(payload[:8])
+		copy(id.nano.entropy[:], payload[8:])
+		return id, nil
+	}
+
+	v, err := ksuid.FromBytes(payload)
+	if err != nil {
+		return ID{}, err
+	}
+	id.ksuid = v
+
+	return id, nil
+}
+
+func parseTagged(s string) (id ID, err error) {
+	if len(s) < 3 {
+		return ID{}, errStringSize
+	}
+
+	format, ok := formatFromChar(s[0])
+	if !ok {
+		return ID{}, errStringSize
+	}
+
+	nsLen64, err := strconv.ParseUint(s[1:3], 16, 8)
+	if err != nil {
+		return ID{}, errStringSize
+	}
+	nsLen := int(nsLen64)
+
+	rest := s[3:]
+	if len(rest) != nsLen+(stringEncodedLength-nsLength) {
+		return ID{}, errStringSize
+	}
+
+	ns, err := NewNamespaceN([]byte(rest[:nsLen]))
+	if err != nil {
+		return ID{}, err
+	}
+
+	v, err := ksuid.Parse(rest[nsLen:])
+	if err != nil {
+		return ID{}, err
+	}
+
+	id.format = format
+	id.ns = ns
+	if format == FormatV2Nano {
+		id.nano.ts = binary.BigEndian.Uint64(v[:8])
+		copy(id.nano.entropy[:], v[8:])
+	} else {
+		id.ksuid = v
+	}
+
+	return id, nil
+}
+
+func formatChar(f Format) byte {
+	switch f {
+	case FormatV2KSUIDN:
+		return 'n'
+	case FormatV2Nano:
+		return 't'
+	default:
+		return 0
+	}
+}
+
+func formatFromChar(c byte) (Format, bool) {
+	switch c {
+	case 'n':
+		return FormatV2KSUIDN, true
+	case 't':
+		return FormatV2Nano, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,189 @@
+package ksuidx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewWithFormat(t *testing.T) {
+	t.Run("v2 ksuidn", func(t *testing.T) {
+		ns, err := NewNamespaceN([]byte("order-service"))
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		id := NewWithFormat(ns, FormatV2KSUIDN)
+		if got, want := id.Format(), FormatV2KSUIDN; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := id.Namespace(), ns; !got.Equal(want) {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("v2 nano", func(t *testing.T) {
+		ns, err := NewNamespaceN([]byte("order-service"))
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		id := NewWithFormat(ns, FormatV2Nano)
+		if got, want := id.Format(), FormatV2Nano; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got := time.Now().Unix() - id.Time().Unix(); got > 1 {
+			t.Fatalf("got %v; want <= 1", got)
+		}
+	})
+
+	t.Run("v1 namespace too long", func(t *testing.T) {
+		ns, err := NewNamespaceN([]byte("too-long-for-v1"))
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		_, err = NewRandomWithFormat(ns, FormatV1KSUID3, time.Now())
+		if got, want := err, errNamespaceSize; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+}
+
+func TestID_BytesRoundTrip_V2(t *testing.T) {
+	testCases := map[string]Format{
+		"ksuidn": FormatV2KSUIDN,
+		"nano":   FormatV2Nano,
+	}
+
+	for label, format := range testCases {
+		t.Run(label, func(t *testing.T) {
+			ns, err := NewNamespaceN([]byte("order-service"))
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+
+			want := NewWithFormat(ns, format)
+
+			got, err := FromBytes(want.Bytes())
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestID_StringRoundTrip_V2(t *testing.T) {
+	testCases := map[string]Format{
+		"ksuidn": FormatV2KSUIDN,
+		"nano":   FormatV2Nano,
+	}
+
+	for label, format := range testCases {
+		t.Run(label, func(t *testing.T) {
+			ns, err := NewNamespaceN([]byte("order-service"))
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+
+			want := NewWithFormat(ns, format)
+
+			got, err := Parse(want.String())
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestID_BytesRoundTrip_V2_MinNamespace(t *testing.T) {
+	// a v2 namespace shorter than minNamespaceLengthV2 would make
+	// FormatV2KSUIDN's tagged encoding exactly byteLength bytes long,
+	// colliding with FormatV1KSUID3
+	ns, err := NewNamespaceN([]byte("ab"))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	want := NewWithFormat(ns, FormatV2KSUIDN)
+	if got, want := len(want.Bytes()), byteLength; got == want {
+		t.Fatalf("got %v; want != %v", got, want)
+	}
+
+	got, err := FromBytes(want.Bytes())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := got.Format(), FormatV2KSUIDN; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if !got.Equal(want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestNewNamespaceN_RejectsOneByte(t *testing.T) {
+	_, err := NewNamespaceN([]byte("a"))
+	if got, want := err, errNamespaceLength; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestID_StringRoundTrip_V2_MinNamespace(t *testing.T) {
+	// an empty v2 namespace would make appendV2's tagged encoding exactly
+	// stringEncodedLength characters long, colliding with FormatV1KSUID3's
+	// string encoding
+	ns, err := NewNamespaceN([]byte("ab"))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	want := NewWithFormat(ns, FormatV2KSUIDN)
+	if got, want := len(want.String()), stringEncodedLength; got == want {
+		t.Fatalf("got %v; want != %v", got, want)
+	}
+
+	got, err := Parse(want.String())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := got.Format(), FormatV2KSUIDN; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if !got.Equal(want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestNewRandomWithFormat_RejectsZeroValueNamespace(t *testing.T) {
+	var ns Namespace // zero value, length == 0
+
+	testCases := []Format{FormatV2KSUIDN, FormatV2Nano}
+	for _, format := range testCases {
+		_, err := NewRandomWithFormat(ns, format, time.Now())
+		if got, want := err, errNamespaceLength; got != want {
+			t.Fatalf("format %v: got %v; want %v", format, got, want)
+		}
+	}
+}
+
+func TestParse_UnrecognizedFormat(t *testing.T) {
+	_, err := Parse("bogus-value-that-is-not-an-id")
+	if got, want := err, errStringSize; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestFromBytes_UnrecognizedFormat(t *testing.T) {
+	_, err := FromBytes([]byte{0xff, 0x03, 1, 2, 3})
+	if ok := errors.Is(err, errFormatUnknown); !ok {
+		t.Fatalf("got %v; want %v", err, errFormatUnknown)
+	}
+}
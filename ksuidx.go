@@ -19,8 +19,9 @@ var (
 )
 
 var (
-	errNamespaceSize = fmt.Errorf("valid namespaces are %v bytes", nsLength)
-	errStringSize    = fmt.Errorf("valid encoded strings are %v bytes", stringEncodedLength)
+	errNamespaceSize   = fmt.Errorf("valid namespaces are %v bytes", nsLength)
+	errNamespaceLength = fmt.Errorf("valid v2 namespaces are %v-%v bytes", minNamespaceLengthV2, maxNamespaceLength)
+	errStringSize      = fmt.Errorf("valid encoded strings are %v bytes", stringEncodedLength)
 )
 
 const (
@@ -28,20 +29,35 @@ const (
 	baseLength          = 20 // length of segment's ksuid
 	nsLength            = byteLength - baseLength
 	stringEncodedLength = 30
+
+	// maxNamespaceLength is the largest namespace supported by the v2 id
+	// formats, see Format
+	maxNamespaceLength = 32
+
+	// minNamespaceLengthV2 is the smallest namespace supported by the v2 id
+	// formats. 1 is deliberately excluded: a 1-byte namespace would make
+	// FormatV2KSUIDN's tagged encoding exactly byteLength (23) bytes long,
+	// indistinguishable from FormatV1KSUID3
+	minNamespaceLengthV2 = 2
 )
 
 // IDs are 23 bytes:
 // 	00-02: 3 ascii character namespace
 //  03-06: uint32 BE UTC timestamp with custom epoch
 //  07-22 byte: random "payload"
+//
+// ids minted with a v2 Format are laid out differently, see Format
 type ID struct {
-	ns    Namespace
-	ksuid ksuid.KSUID
+	format Format
+	ns     Namespace
+	ksuid  ksuid.KSUID
+	nano   nanoID
 }
 
 // Constructs an ID from either a 23 byte ID representation OR a 20 byte KSUID
 // representation.  If a 20 byte KSUID representation is used, the Unknown
-// namespace prefix will be used
+// namespace prefix will be used. Bytes that don't match either of those
+// lengths are sniffed for a v2 Format tag, see Format.
 func FromBytes(b []byte) (id ID, err error) {
 	switch len(b) {
 	case byteLength:
@@ -50,11 +66,15 @@ func FromBytes(b []byte) (id ID, err error) {
 			return ID{}, err
 		}
 
-		copy(id.ns[:], b[0:nsLength])
+		var ns Namespace
+		ns.length = nsLength
+		copy(ns.data[:], b[0:nsLength])
+
+		id.ns = ns
 		id.ksuid = v
 		return id, nil
 
-	default: // when b is a ksuid
+	case baseLength: // when b is a bare ksuid
 		v, err := ksuid.FromBytes(b)
 		if err != nil {
 			return ID{}, err
@@ -62,12 +82,18 @@ func FromBytes(b []byte) (id ID, err error) {
 		id.ns = Unknown
 		id.ksuid = v
 		return id, nil
+
+	default:
+		return fromBytesTagged(b)
 	}
 }
 
-// Parse a string representation of an ID
+// Parse a string representation of an ID. Strings that don't match the v1
+// lengths (27 for a bare ksuid, 30 for a namespaced id) are sniffed for a
+// leading v2 Format tag.
 func Parse(s string) (id ID, err error) {
-	if length := len(s); length == stringEncodedLength-nsLength {
+	switch len(s) {
+	case stringEncodedLength - nsLength:
 		v, err := ksuid.Parse(s)
 		if err != nil {
 			return ID{}, err
@@ -76,23 +102,37 @@ func Parse(s string) (id ID, err error) {
 			ns:    Unknown,
 			ksuid: v,
 		}, nil
-	} else if length != stringEncodedLength {
-		return ID{}, errStringSize
-	}
 
-	ns, err := NewNamespace(s[0:nsLength])
-	if err != nil {
-		return ID{}, err
+	case stringEncodedLength:
+		ns, err := NewNamespace(s[0:nsLength])
+		if err != nil {
+			return ID{}, err
+		}
+
+		v, err := ksuid.Parse(s[nsLength:])
+		if err != nil {
+			return ID{}, err
+		}
+
+		id.ns = ns
+		id.ksuid = v
+
+		return id, nil
+
+	default:
+		return parseTagged(s)
 	}
+}
 
-	v, err := ksuid.Parse(s[nsLength:])
+// ParseNS parses s, a bare ksuid or ksuidx string, and stamps the result with
+// ns, the namespace to use regardless of what (if anything) was encoded in s.
+// This is handy for upgrading ids minted before a namespace was adopted.
+func ParseNS(s string, ns Namespace) (id ID, err error) {
+	id, err = Parse(s)
 	if err != nil {
 		return ID{}, err
 	}
-
 	id.ns = ns
-	id.ksuid = v
-
 	return id, nil
 }
 
@@ -126,12 +166,19 @@ func NewRandomWithTime(ns Namespace, t time.Time) (id ID, err error) {
 // Append appends the string representation of i to b, returning a slice to a
 // potentially larger memory area.
 func (i ID) Append(b []byte) []byte {
-	b = append(b, i.ns[:]...)
+	if i.format.isV2() {
+		return i.appendV2(b)
+	}
+	b = i.ns.Append(b)
 	return i.ksuid.Append(b)
 }
 
-// Bytes returns []byte representation of ID (23 bytes long)
+// Bytes returns []byte representation of ID. v1 ids are 23 bytes; v2 ids are
+// variable length, see Format
 func (i ID) Bytes() []byte {
+	if i.format.isV2() {
+		return i.bytesV2()
+	}
 	b := make([]byte, 0, byteLength)
 	b = i.ns.Append(b)
 	b = append(b, i.ksuid[:]...)
@@ -140,12 +187,14 @@ func (i ID) Bytes() []byte {
 
 // Equal to provided ID
 func (i ID) Equal(that ID) bool {
-	return i.ns.Equal(that.ns) && bytes.Equal(i.ksuid[:], that.ksuid[:])
+	return i.format == that.format &&
+		i.ns.Equal(that.ns) &&
+		bytes.Equal(i.payload(), that.payload())
 }
 
 // IsNil returns true if this is a "nil" ID
 func (i ID) IsNil() bool {
-	return i.ns.Equal(Unknown) && i.ksuid.IsNil()
+	return i.format == FormatV1KSUID3 && i.ns.Equal(Unknown) && i.ksuid.IsNil()
 }
 
 // MarshalJSON implements json.Marshaler
@@ -157,7 +206,13 @@ func (i ID) MarshalJSON() ([]byte, error) {
 	return b, nil
 }
 
-// KSUID returns underlying KSUID
+// Format returns the on-the-wire Format used to encode i
+func (i ID) Format() Format {
+	return i.format
+}
+
+// KSUID returns underlying KSUID. For ids minted with FormatV2Nano, which
+// has no KSUID-resolution timestamp, this is the zero value
 func (i ID) KSUID() ksuid.KSUID {
 	return i.ksuid
 }
@@ -173,8 +228,11 @@ func (i ID) String() string {
 	return string(i.Append(b))
 }
 
-// Time represents timestamp portion of the KSUID as a Time object
+// Time represents timestamp portion of the id as a Time object
 func (i ID) Time() time.Time {
+	if i.format == FormatV2Nano {
+		return time.Unix(0, int64(i.nano.ts))
+	}
 	return i.ksuid.Time()
 }
 
@@ -199,8 +257,13 @@ func (i *ID) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-// Namespace to enable easy identification by user
-type Namespace [nsLength]byte
+// Namespace to enable easy identification by user. v1 namespaces are
+// exactly 3 ascii characters; v2 namespaces (see Format) may be up to
+// maxNamespaceLength bytes
+type Namespace struct {
+	length byte
+	data   [maxNamespaceLength]byte
+}
 
 // NewNamespace must be EXACTLY 3 ascii characters
 func NewNamespace(v string) (Namespace, error) {
@@ -210,7 +273,26 @@ func NewNamespace(v string) (Namespace, error) {
 	}
 
 	var ns Namespace
-	copy(ns[:], b[:])
+	ns.length = nsLength
+	copy(ns.data[:], b)
+	return ns, nil
+}
+
+// NewNamespaceN constructs a Namespace from minNamespaceLengthV2 to
+// maxNamespaceLength bytes, for use with the v2 id formats, where namespaces
+// are no longer capped at 3 ascii characters. The floor of
+// minNamespaceLengthV2 keeps FormatV2KSUIDN's tagged binary encoding
+// (2+len(ns)+20 bytes) from ever colliding with FormatV1KSUID3's fixed
+// 23-byte encoding, which FromBytes must otherwise be able to tell apart by
+// length alone
+func NewNamespaceN(b []byte) (Namespace, error) {
+	if len(b) < minNamespaceLengthV2 || len(b) > maxNamespaceLength {
+		return Namespace{}, errNamespaceLength
+	}
+
+	var ns Namespace
+	ns.length = byte(len(b))
+	copy(ns.data[:], b)
 	return ns, nil
 }
 
@@ -226,17 +308,16 @@ func MustNamespace(v string) Namespace {
 
 // Append the namespace to the provided byte array
 func (n Namespace) Append(b []byte) []byte {
-	return append(b, n[:]...)
+	return append(b, n.raw()...)
 }
 
 func (n Namespace) raw() []byte {
-	var b [3]byte = n
-	return b[:]
+	return n.data[:n.length]
 }
 
 // Bytes returns a []byte representation of the Namespace
 func (n Namespace) Bytes() []byte {
-	b := make([]byte, 0, nsLength)
+	b := make([]byte, 0, n.length)
 	return n.Append(b)
 }
 
@@ -246,7 +327,7 @@ func (n Namespace) Equal(that interface{}) bool {
 	case Namespace:
 		return bytes.Equal(n.raw(), v.raw())
 	case [3]byte:
-		return bytes.Equal(n.raw(), v[:])
+		return n.length == nsLength && bytes.Equal(n.raw(), v[:])
 	case []byte:
 		return bytes.Equal(n.raw(), v)
 	case string:
@@ -258,6 +339,6 @@ func (n Namespace) Equal(that interface{}) bool {
 
 // String view of Namespace
 func (n Namespace) String() string {
-	b := make([]byte, 0, nsLength)
+	b := make([]byte, 0, n.length)
 	return string(n.Append(b))
 }
@@ -0,0 +1,144 @@
+package ksuidx
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	errNamespaceRegistered    = fmt.Errorf("namespace already registered")
+	errNamespaceNotRegistered = fmt.Errorf("namespace not registered")
+)
+
+// NamespaceMeta describes a Namespace registered with a NamespaceRegistry.
+type NamespaceMeta struct {
+	// Name is a short, human-readable label for the namespace
+	Name string
+
+	// Description explains what the namespace is used for
+	Description string
+
+	// EntityType optionally identifies the kind of entity the namespace
+	// produces ids for e.g. "user" or "order"
+	EntityType string
+}
+
+// NamespaceRegistry coordinates the set of Namespace values an application
+// uses, allowing metadata to be attached to each one and duplicate or
+// unknown namespaces to be rejected.
+type NamespaceRegistry struct {
+	mu   sync.RWMutex
+	meta map[Namespace]NamespaceMeta
+}
+
+// NewNamespaceRegistry constructs an empty NamespaceRegistry
+func NewNamespaceRegistry() *NamespaceRegistry {
+	return &NamespaceRegistry{
+		meta: map[Namespace]NamespaceMeta{},
+	}
+}
+
+// Register associates meta with ns, returning an error if ns has already
+// been registered
+func (r *NamespaceRegistry) Register(ns Namespace, meta NamespaceMeta) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.meta[ns]; exists {
+		return fmt.Errorf("%w: %v", errNamespaceRegistered, ns)
+	}
+
+	r.meta[ns] = meta
+	return nil
+}
+
+// MustRegister is like Register, but panics if ns has already been
+// registered
+func (r *NamespaceRegistry) MustRegister(ns Namespace, meta NamespaceMeta) {
+	if err := r.Register(ns, meta); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterFromString parses s as a Namespace and registers it with meta, a
+// convenience for callers that don't already hold a Namespace value
+func (r *NamespaceRegistry) RegisterFromString(s string, meta NamespaceMeta) (Namespace, error) {
+	ns, err := NewNamespace(s)
+	if err != nil {
+		return Namespace{}, err
+	}
+
+	if err := r.Register(ns, meta); err != nil {
+		return Namespace{}, err
+	}
+
+	return ns, nil
+}
+
+// Lookup returns the NamespaceMeta registered for ns, if any
+func (r *NamespaceRegistry) Lookup(ns Namespace) (NamespaceMeta, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	meta, ok := r.meta[ns]
+	return meta, ok
+}
+
+// LookupString parses s as a Namespace and looks it up, returning false if s
+// isn't a valid namespace or isn't registered
+func (r *NamespaceRegistry) LookupString(s string) (NamespaceMeta, bool) {
+	ns, err := NewNamespace(s)
+	if err != nil {
+		return NamespaceMeta{}, false
+	}
+	return r.Lookup(ns)
+}
+
+// All returns the registered namespaces, sorted for stable iteration
+func (r *NamespaceRegistry) All() []Namespace {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Namespace, 0, len(r.meta))
+	for ns := range r.meta {
+		out = append(out, ns)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].String() < out[j].String()
+	})
+
+	return out
+}
+
+// ParseWith is like Parse, but additionally requires the decoded namespace to
+// be registered with r, allowing applications to enforce a closed namespace
+// world rather than silently accepting typos or foreign ids
+func ParseWith(s string, r *NamespaceRegistry) (ID, error) {
+	id, err := Parse(s)
+	if err != nil {
+		return ID{}, err
+	}
+
+	if _, ok := r.Lookup(id.ns); !ok {
+		return ID{}, fmt.Errorf("%w: %v", errNamespaceNotRegistered, id.ns)
+	}
+
+	return id, nil
+}
+
+// FromBytesWith is like FromBytes, but additionally requires the decoded
+// namespace to be registered with r
+func FromBytesWith(b []byte, r *NamespaceRegistry) (ID, error) {
+	id, err := FromBytes(b)
+	if err != nil {
+		return ID{}, err
+	}
+
+	if _, ok := r.Lookup(id.ns); !ok {
+		return ID{}, fmt.Errorf("%w: %v", errNamespaceNotRegistered, id.ns)
+	}
+
+	return id, nil
+}
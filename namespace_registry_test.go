@@ -0,0 +1,159 @@
+package ksuidx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNamespaceRegistry_Register(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		r := NewNamespaceRegistry()
+		ns := MustNamespace("usr")
+		meta := NamespaceMeta{Name: "user", EntityType: "user"}
+
+		err := r.Register(ns, meta)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		got, ok := r.Lookup(ns)
+		if !ok {
+			t.Fatalf("got %v; want true", ok)
+		}
+		if !reflect.DeepEqual(got, meta) {
+			t.Fatalf("got %v; want %v", got, meta)
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		r := NewNamespaceRegistry()
+		ns := MustNamespace("usr")
+
+		if err := r.Register(ns, NamespaceMeta{}); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		err := r.Register(ns, NamespaceMeta{})
+		if ok := errors.Is(err, errNamespaceRegistered); !ok {
+			t.Fatalf("got %v; want %v", err, errNamespaceRegistered)
+		}
+	})
+}
+
+func TestNamespaceRegistry_MustRegister(t *testing.T) {
+	t.Run("panics on duplicate", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("got nil; want panic")
+			}
+		}()
+
+		r := NewNamespaceRegistry()
+		ns := MustNamespace("usr")
+		r.MustRegister(ns, NamespaceMeta{})
+		r.MustRegister(ns, NamespaceMeta{})
+	})
+}
+
+func TestNamespaceRegistry_RegisterFromString(t *testing.T) {
+	r := NewNamespaceRegistry()
+	ns, err := r.RegisterFromString("usr", NamespaceMeta{Name: "user"})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := ns, MustNamespace("usr"); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestNamespaceRegistry_LookupString(t *testing.T) {
+	r := NewNamespaceRegistry()
+	r.MustRegister(MustNamespace("usr"), NamespaceMeta{Name: "user"})
+
+	t.Run("found", func(t *testing.T) {
+		meta, ok := r.LookupString("usr")
+		if !ok {
+			t.Fatalf("got %v; want true", ok)
+		}
+		if got, want := meta.Name, "user"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		_, ok := r.LookupString("ord")
+		if ok {
+			t.Fatalf("got %v; want false", ok)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, ok := r.LookupString("too-long")
+		if ok {
+			t.Fatalf("got %v; want false", ok)
+		}
+	})
+}
+
+func TestNamespaceRegistry_All(t *testing.T) {
+	r := NewNamespaceRegistry()
+	r.MustRegister(MustNamespace("usr"), NamespaceMeta{})
+	r.MustRegister(MustNamespace("ord"), NamespaceMeta{})
+
+	got := r.All()
+	want := []Namespace{MustNamespace("ord"), MustNamespace("usr")}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestParseWith(t *testing.T) {
+	r := NewNamespaceRegistry()
+	ns := MustNamespace("usr")
+	r.MustRegister(ns, NamespaceMeta{})
+
+	t.Run("registered", func(t *testing.T) {
+		want := New(ns)
+		got, err := ParseWith(want.String(), r)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		other := New(MustNamespace("ord"))
+		_, err := ParseWith(other.String(), r)
+		if ok := errors.Is(err, errNamespaceNotRegistered); !ok {
+			t.Fatalf("got %v; want %v", err, errNamespaceNotRegistered)
+		}
+	})
+}
+
+func TestFromBytesWith(t *testing.T) {
+	r := NewNamespaceRegistry()
+	ns := MustNamespace("usr")
+	r.MustRegister(ns, NamespaceMeta{})
+
+	t.Run("registered", func(t *testing.T) {
+		want := New(ns)
+		got, err := FromBytesWith(want.Bytes(), r)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		other := New(MustNamespace("ord"))
+		_, err := FromBytesWith(other.Bytes(), r)
+		if ok := errors.Is(err, errNamespaceNotRegistered); !ok {
+			t.Fatalf("got %v; want %v", err, errNamespaceNotRegistered)
+		}
+	})
+}
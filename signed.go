@@ -0,0 +1,280 @@
+package ksuidx
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+const (
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// signedByteLength is the canonical binary length of a SignedID: a v1
+	// ID followed by an ed25519 signature over it
+	signedByteLength = byteLength + ed25519.SignatureSize
+)
+
+// signedIDEncodedLength is the number of base62 characters needed to encode
+// signedByteLength bytes without losing leading zero bytes
+var signedIDEncodedLength = base62Width(signedByteLength)
+
+var (
+	errSignedSize       = fmt.Errorf("valid signed ids are %v bytes", signedByteLength)
+	errSignatureInvalid = fmt.Errorf("signature invalid or namespace not registered")
+	errBase62Char       = fmt.Errorf("invalid base62 character")
+)
+
+// Signer produces a signature over an arbitrary message, e.g. the canonical
+// encoding of an ID
+type Signer interface {
+	Sign(message []byte) []byte
+}
+
+// Verifier checks a signature over message, scoped to the namespace the
+// message's ID belongs to, so that different namespaces can be signed by
+// different keys
+type Verifier interface {
+	Verify(ns Namespace, message, sig []byte) bool
+}
+
+// ed25519Signer adapts an ed25519.PrivateKey to the Signer interface
+type ed25519Signer ed25519.PrivateKey
+
+// NewSigner returns a Signer backed by priv
+func NewSigner(priv ed25519.PrivateKey) Signer {
+	return ed25519Signer(priv)
+}
+
+// Sign implements Signer
+func (s ed25519Signer) Sign(message []byte) []byte {
+	return ed25519.Sign(ed25519.PrivateKey(s), message)
+}
+
+// KeyRegistry is a Verifier backed by a set of per-namespace ed25519 public
+// keys
+type KeyRegistry struct {
+	mu   sync.RWMutex
+	keys map[Namespace]ed25519.PublicKey
+}
+
+// NewKeyRegistry constructs an empty KeyRegistry
+func NewKeyRegistry() *KeyRegistry {
+	return &KeyRegistry{
+		keys: map[Namespace]ed25519.PublicKey{},
+	}
+}
+
+// Register associates pub with ns, replacing any key previously registered
+// for ns
+func (r *KeyRegistry) Register(ns Namespace, pub ed25519.PublicKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[ns] = pub
+}
+
+// Lookup returns the public key registered for ns, if any
+func (r *KeyRegistry) Lookup(ns Namespace) (ed25519.PublicKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	pub, ok := r.keys[ns]
+	return pub, ok
+}
+
+// Verify implements Verifier, rejecting sig if ns has no registered key
+func (r *KeyRegistry) Verify(ns Namespace, message, sig []byte) bool {
+	pub, ok := r.Lookup(ns)
+	if !ok {
+		return false
+	}
+	return ed25519.Verify(pub, message, sig)
+}
+
+// SignedID is an ID together with an ed25519 signature over its canonical
+// v1 encoding, suitable for sharing with clients that must not be able to
+// forge or tamper with the id
+type SignedID struct {
+	id  ID
+	sig [ed25519.SignatureSize]byte
+}
+
+// NewSigned mints a new v1 ID for ns and signs it with s. ns must be a v1,
+// 3-byte namespace: the signed message is always the canonical 23-byte v1
+// encoding, so a longer v2 namespace would silently produce a payload other
+// than signedByteLength bytes
+func NewSigned(ns Namespace, s Signer) (SignedID, error) {
+	if ns.length != nsLength {
+		return SignedID{}, errNamespaceSize
+	}
+
+	id, err := NewRandom(ns)
+	if err != nil {
+		return SignedID{}, err
+	}
+
+	signed := SignedID{id: id}
+	copy(signed.sig[:], s.Sign(id.Bytes()))
+
+	return signed, nil
+}
+
+// ParseSigned parses str, a string produced by SignedID.String, and
+// verifies it against v
+func ParseSigned(str string, v Verifier) (SignedID, error) {
+	if len(str) != signedIDEncodedLength {
+		return SignedID{}, errSignedSize
+	}
+
+	b, err := decodeBase62(str, signedByteLength)
+	if err != nil {
+		return SignedID{}, err
+	}
+
+	return FromBytesSigned(b, v)
+}
+
+// FromBytesSigned parses b, the canonical id-plus-signature encoding
+// produced by SignedID.Bytes, and verifies it against v
+func FromBytesSigned(b []byte, v Verifier) (SignedID, error) {
+	if len(b) != signedByteLength {
+		return SignedID{}, errSignedSize
+	}
+
+	id, err := FromBytes(b[:byteLength])
+	if err != nil {
+		return SignedID{}, err
+	}
+
+	signed := SignedID{id: id}
+	copy(signed.sig[:], b[byteLength:])
+
+	if err := signed.Verify(v); err != nil {
+		return SignedID{}, err
+	}
+
+	return signed, nil
+}
+
+// ID returns the inner, unsigned ID
+func (s SignedID) ID() ID {
+	return s.id
+}
+
+// Signature returns the raw ed25519 signature bytes
+func (s SignedID) Signature() []byte {
+	return append([]byte(nil), s.sig[:]...)
+}
+
+// Verify checks the signature against v, returning an error if it's invalid,
+// if s's namespace has no registered key, or if s is the Nil id
+func (s SignedID) Verify(v Verifier) error {
+	if s.id.IsNil() {
+		return errSignatureInvalid
+	}
+	if !v.Verify(s.id.ns, s.id.Bytes(), s.sig[:]) {
+		return errSignatureInvalid
+	}
+	return nil
+}
+
+// Bytes returns the canonical binary encoding of s: the inner id's v1
+// encoding followed by the raw signature
+func (s SignedID) Bytes() []byte {
+	b := make([]byte, 0, signedByteLength)
+	b = append(b, s.id.Bytes()...)
+	return append(b, s.sig[:]...)
+}
+
+// String returns the base62 encoding of s.Bytes(), using the same alphabet
+// as KSUID
+func (s SignedID) String() string {
+	return encodeBase62(s.Bytes(), signedIDEncodedLength)
+}
+
+// MarshalJSON implements json.Marshaler
+func (s SignedID) MarshalJSON() ([]byte, error) {
+	b := make([]byte, 0, signedIDEncodedLength+2)
+	b = append(b, '"')
+	b = append(b, s.String()...)
+	b = append(b, '"')
+	return b, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It decodes the signed string
+// but, per the json.Unmarshaler contract, has no way to accept a Verifier;
+// callers that need to trust the result must call Verify explicitly
+func (s *SignedID) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+
+	if len(str) != signedIDEncodedLength {
+		return errSignedSize
+	}
+
+	raw, err := decodeBase62(str, signedByteLength)
+	if err != nil {
+		return err
+	}
+
+	id, err := FromBytes(raw[:byteLength])
+	if err != nil {
+		return err
+	}
+
+	s.id = id
+	copy(s.sig[:], raw[byteLength:])
+
+	return nil
+}
+
+// base62Width returns the number of base62 digits needed to encode any value
+// of numBytes bytes without losing leading zero bytes
+func base62Width(numBytes int) int {
+	return int(math.Ceil(float64(numBytes*8) / math.Log2(62)))
+}
+
+// encodeBase62 encodes b as exactly width base62 digits, left padding with
+// the alphabet's zero digit as needed
+func encodeBase62(b []byte, width int) string {
+	n := new(big.Int).SetBytes(b)
+	base := big.NewInt(62)
+	mod := new(big.Int)
+
+	out := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		out[i] = base62Alphabet[mod.Int64()]
+	}
+
+	return string(out)
+}
+
+// decodeBase62 decodes s into exactly byteLen bytes, left padding with zero
+// bytes as needed
+func decodeBase62(s string, byteLen int) ([]byte, error) {
+	n := new(big.Int)
+	base := big.NewInt(62)
+
+	for i := 0; i < len(s); i++ {
+		idx := strings.IndexByte(base62Alphabet, s[i])
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: %q", errBase62Char, s[i])
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+
+	raw := n.Bytes()
+	if len(raw) > byteLen {
+		return nil, errBase62Char
+	}
+
+	out := make([]byte, byteLen)
+	copy(out[byteLen-len(raw):], raw)
+	return out, nil
+}
@@ -0,0 +1,139 @@
+package ksuidx
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSignedID_RoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	ns := MustNamespace("usr")
+	keys := NewKeyRegistry()
+	keys.Register(ns, pub)
+
+	want, err := NewSigned(ns, NewSigner(priv))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	t.Run("string round trip", func(t *testing.T) {
+		got, err := ParseSigned(want.String(), keys)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if !got.ID().Equal(want.ID()) {
+			t.Fatalf("got %v; want %v", got.ID(), want.ID())
+		}
+	})
+
+	t.Run("bytes round trip", func(t *testing.T) {
+		got, err := FromBytesSigned(want.Bytes(), keys)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if !got.ID().Equal(want.ID()) {
+			t.Fatalf("got %v; want %v", got.ID(), want.ID())
+		}
+	})
+
+	t.Run("json round trip", func(t *testing.T) {
+		b, err := want.MarshalJSON()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		var got SignedID
+		if err := got.UnmarshalJSON(b); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if err := got.Verify(keys); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+}
+
+func TestSignedID_Verify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	ns := MustNamespace("usr")
+
+	t.Run("namespace not registered", func(t *testing.T) {
+		signed, err := NewSigned(ns, NewSigner(priv))
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		empty := NewKeyRegistry()
+		err = signed.Verify(empty)
+		if ok := errors.Is(err, errSignatureInvalid); !ok {
+			t.Fatalf("got %v; want %v", err, errSignatureInvalid)
+		}
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		signed, err := NewSigned(ns, NewSigner(priv))
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		otherPub, _, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		keys := NewKeyRegistry()
+		keys.Register(ns, otherPub)
+
+		err = signed.Verify(keys)
+		if ok := errors.Is(err, errSignatureInvalid); !ok {
+			t.Fatalf("got %v; want %v", err, errSignatureInvalid)
+		}
+	})
+
+	t.Run("nil never verifies", func(t *testing.T) {
+		keys := NewKeyRegistry()
+		keys.Register(Unknown, pub)
+
+		signed := SignedID{id: Nil}
+		err := signed.Verify(keys)
+		if ok := errors.Is(err, errSignatureInvalid); !ok {
+			t.Fatalf("got %v; want %v", err, errSignatureInvalid)
+		}
+	})
+}
+
+func TestParseSigned_BadLength(t *testing.T) {
+	_, err := ParseSigned("too-short", NewKeyRegistry())
+	if ok := errors.Is(err, errSignedSize); !ok {
+		t.Fatalf("got %v; want %v", err, errSignedSize)
+	}
+}
+
+func TestNewSigned_RejectsV2Namespace(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	ns, err := NewNamespaceN([]byte("order-service"))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	_, err = NewSigned(ns, NewSigner(priv))
+	if got, want := err, errNamespaceSize; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}